@@ -0,0 +1,86 @@
+package weightedcache
+
+import (
+	"sync"
+	"time"
+)
+
+// Upsert inserts an object into the cache, or, if key already exists,
+// replaces its value and weight and moves it to the head instead of
+// returning the "key already exists" error Insert would.
+func (c *WeightedCacheOf[K, V]) Upsert(key K, value V, weight int) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if n, found := c.lookup[key]; found {
+		c.removeLocked(n)
+	}
+
+	return c.insertLocked(key, value, weight, time.Time{})
+}
+
+// call tracks a single in-flight GetOrCompute invocation for a key, so
+// concurrent callers that miss the cache at the same time wait on one
+// another instead of each invoking fn.
+type call[V any] struct {
+	wg  sync.WaitGroup
+	val V
+	err error
+}
+
+// GetOrCompute returns the cached value for key if present, otherwise it
+// invokes fn exactly once to populate the cache, even if multiple
+// goroutines call GetOrCompute for the same key concurrently. Concurrent
+// callers for the same key block on the first call's result.
+func (c *WeightedCacheOf[K, V]) GetOrCompute(key K, weight int, fn func() (V, error)) (V, error) {
+	if v, ok := c.Retrieve(key); ok {
+		return v, nil
+	}
+
+	c.computeMutex.Lock()
+
+	if cl, inFlight := c.calls[key]; inFlight {
+		c.computeMutex.Unlock()
+		cl.wg.Wait()
+
+		return cl.val, cl.err
+	}
+
+	// a call that finished after the Retrieve above has already cached its value
+	c.mutex.Lock()
+	if n, cached := c.lookup[key]; cached && !n.expired() {
+		v := n.value
+		c.mutex.Unlock()
+		c.computeMutex.Unlock()
+
+		return v, nil
+	}
+	c.mutex.Unlock()
+
+	cl := &call[V]{}
+	cl.wg.Add(1)
+
+	if c.calls == nil {
+		c.calls = make(map[K]*call[V])
+	}
+
+	c.calls[key] = cl
+	c.computeMutex.Unlock()
+
+	func() {
+		defer func() {
+			c.computeMutex.Lock()
+			delete(c.calls, key)
+			c.computeMutex.Unlock()
+
+			cl.wg.Done()
+		}()
+
+		cl.val, cl.err = fn()
+		if cl.err == nil {
+			_ = c.Insert(key, cl.val, weight)
+		}
+	}()
+
+	return cl.val, cl.err
+}
@@ -0,0 +1,154 @@
+package weightedcache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUpsertReplacesValueAndFiresOnEvict(t *testing.T) {
+	c := New(10)
+
+	if err := c.Insert("a", "handle1", 5); err != nil {
+		t.Fatal(err)
+	}
+
+	var evicted []interface{}
+	c.SetOnEvict(func(key string, value interface{}, weight int) {
+		evicted = append(evicted, value)
+	})
+
+	if err := c.Upsert("a", "handle2", 5); err != nil {
+		t.Fatal(err)
+	}
+
+	v, ok := c.Retrieve("a")
+	if !ok || v != "handle2" {
+		t.Fatalf("expected updated value handle2, got %v ok=%v", v, ok)
+	}
+
+	if c.Weight() != 5 {
+		t.Fatalf("expected weight 5, got %d", c.Weight())
+	}
+
+	if len(evicted) != 1 || evicted[0] != "handle1" {
+		t.Fatalf("expected onEvict to fire once with the replaced value, got %v", evicted)
+	}
+}
+
+func TestUpsertInsertsNewKey(t *testing.T) {
+	c := New(10)
+
+	if err := c.Upsert("a", 1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := c.Retrieve("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1, got %v ok=%v", v, ok)
+	}
+}
+
+func TestGetOrComputeRunsFnExactlyOnceUnderConcurrency(t *testing.T) {
+	c := New(10)
+
+	var calls int32
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 20)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			v, err := c.GetOrCompute("k", 1, func() (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				return 42, nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+
+			results[i] = v
+		}(i)
+	}
+
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected fn to run exactly once, ran %d times", got)
+	}
+
+	for _, r := range results {
+		if r != 42 {
+			t.Fatalf("expected every caller to see 42, got %v", r)
+		}
+	}
+}
+
+func TestGetOrComputeReturnsCachedValueWithoutRecomputing(t *testing.T) {
+	c := New(10)
+
+	if _, err := c.GetOrCompute("k", 1, func() (interface{}, error) {
+		return 1, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls int32
+
+	v, err := c.GetOrCompute("k", 1, func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return 2, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v != 1 {
+		t.Fatalf("expected cached value 1, got %v", v)
+	}
+
+	if calls != 0 {
+		t.Fatal("expected fn not to be invoked for an already-cached key")
+	}
+}
+
+func TestGetOrComputeRecoversKeyAfterPanickingFn(t *testing.T) {
+	c := New(10)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic to propagate out of GetOrCompute")
+			}
+		}()
+
+		_, _ = c.GetOrCompute("k", 1, func() (interface{}, error) {
+			panic("boom")
+		})
+	}()
+
+	done := make(chan struct{})
+
+	go func() {
+		v, err := c.GetOrCompute("k", 1, func() (interface{}, error) {
+			return 7, nil
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		if v != 7 {
+			t.Errorf("expected 7, got %v", v)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("GetOrCompute is stuck on a key poisoned by a prior panicking fn")
+	}
+}
@@ -0,0 +1,31 @@
+package weightedcache
+
+// Policy selects the eviction strategy a WeightedCacheOf uses to make room
+// for new entries once the weight budget is exceeded.
+type Policy int
+
+const (
+	// PolicyLRU evicts the least-recently-used entry. Retrieve promotes the
+	// entry to the head of the list, so every read relinks the list.
+	PolicyLRU Policy = iota
+
+	// PolicySIEVE evicts using the SIEVE algorithm: Retrieve only sets a
+	// per-node visited bit and never relinks the list, while eviction walks
+	// a hand backwards through the list clearing visited bits until it
+	// finds an unvisited node to evict. This trades LRU's recency
+	// precision for reads that never relink the list; Retrieve still takes
+	// the cache's mutex under either policy.
+	PolicySIEVE
+)
+
+// NewSIEVE creates a new WeightedCache (string-keyed, interface{}-valued)
+// using the SIEVE eviction policy.
+func NewSIEVE(budget int) *WeightedCache {
+	return NewSIEVEOf[string, interface{}](budget)
+}
+
+// NewSIEVEOf creates a new generic WeightedCacheOf[K, V] using the SIEVE
+// eviction policy.
+func NewSIEVEOf[K comparable, V any](budget int) *WeightedCacheOf[K, V] {
+	return newOf[K, V](budget, PolicySIEVE)
+}
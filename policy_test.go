@@ -0,0 +1,64 @@
+package weightedcache
+
+import "testing"
+
+func TestSIEVEEvictsUnvisitedFirst(t *testing.T) {
+	c := NewSIEVE(3)
+
+	mustInsert(t, c, "a", 1, 1)
+	mustInsert(t, c, "b", 2, 1)
+	mustInsert(t, c, "c", 3, 1)
+
+	// mark a and b as visited; c is left unvisited
+	c.Retrieve("a")
+	c.Retrieve("b")
+
+	mustInsert(t, c, "d", 4, 1)
+
+	if _, ok := c.Retrieve("c"); ok {
+		t.Fatal("expected c (unvisited) to be evicted")
+	}
+
+	if _, ok := c.Retrieve("a"); !ok {
+		t.Fatal("expected a (visited) to survive")
+	}
+
+	if _, ok := c.Retrieve("d"); !ok {
+		t.Fatal("expected newly inserted d to be present")
+	}
+}
+
+func TestSIEVEHandWrapsAroundToTail(t *testing.T) {
+	c := NewSIEVE(2)
+
+	mustInsert(t, c, "a", 1, 1)
+	mustInsert(t, c, "b", 2, 1)
+
+	// visit everything, forcing the hand to clear every bit and wrap
+	// around from head back to tail before finding a victim
+	c.Retrieve("a")
+	c.Retrieve("b")
+
+	if err := c.Insert("c", 3, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	remaining := 0
+	for _, k := range []string{"a", "b", "c"} {
+		if _, ok := c.Retrieve(k); ok {
+			remaining++
+		}
+	}
+
+	if remaining != 2 {
+		t.Fatalf("expected exactly 2 entries to remain after eviction, got %d", remaining)
+	}
+}
+
+func mustInsert(t *testing.T, c *WeightedCache, key string, value interface{}, weight int) {
+	t.Helper()
+
+	if err := c.Insert(key, value, weight); err != nil {
+		t.Fatalf("Insert(%q): %v", key, err)
+	}
+}
@@ -0,0 +1,80 @@
+package weightedcache
+
+import "hash/fnv"
+
+// ShardedWeightedCache partitions the keyspace across N independently
+// locked WeightedCache shards, chosen by FNV-1a hash of the key, so that
+// concurrent operations on different keys don't contend on a single
+// mutex. The total weight budget is divided evenly across shards.
+type ShardedWeightedCache struct {
+	shards []*WeightedCache
+}
+
+// NewSharded creates a ShardedWeightedCache with n shards sharing the
+// given total weight budget.
+func NewSharded(budget, n int) *ShardedWeightedCache {
+	if n < 1 {
+		n = 1
+	}
+
+	shards := make([]*WeightedCache, n)
+	perShard := budget / n
+	remainder := budget % n
+
+	for i := range shards {
+		shardBudget := perShard
+		if i < remainder {
+			shardBudget++
+		}
+
+		shards[i] = New(shardBudget)
+	}
+
+	return &ShardedWeightedCache{shards: shards}
+}
+
+func (c *ShardedWeightedCache) shardFor(key string) *WeightedCache {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return c.shards[h.Sum32()%uint32(len(c.shards))]
+}
+
+// Insert inserts an object into the shard that owns key.
+func (c *ShardedWeightedCache) Insert(key string, value interface{}, weight int) error {
+	return c.shardFor(key).Insert(key, value, weight)
+}
+
+// Retrieve gets an object out of the shard that owns key.
+func (c *ShardedWeightedCache) Retrieve(key string) (interface{}, bool) {
+	return c.shardFor(key).Retrieve(key)
+}
+
+// Clear removes all entries from every shard.
+func (c *ShardedWeightedCache) Clear() {
+	for _, s := range c.shards {
+		s.Clear()
+	}
+}
+
+// Weight gets the combined "weight" of every shard.
+func (c *ShardedWeightedCache) Weight() int {
+	total := 0
+
+	for _, s := range c.shards {
+		total += s.Weight()
+	}
+
+	return total
+}
+
+// Budget gets the combined memory budget of every shard.
+func (c *ShardedWeightedCache) Budget() int {
+	total := 0
+
+	for _, s := range c.shards {
+		total += s.Budget()
+	}
+
+	return total
+}
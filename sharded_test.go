@@ -0,0 +1,69 @@
+package weightedcache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestShardedWeightedCacheInsertRetrieveClear(t *testing.T) {
+	sc := NewSharded(10, 4)
+
+	for i := 0; i < 8; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		if err := sc.Insert(key, i, 1); err != nil {
+			t.Fatalf("Insert(%q): %v", key, err)
+		}
+	}
+
+	for i := 0; i < 8; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		if v, ok := sc.Retrieve(key); !ok || v != i {
+			t.Fatalf("Retrieve(%q) = %v, %v; want %d, true", key, v, ok, i)
+		}
+	}
+
+	sc.Clear()
+
+	if w := sc.Weight(); w != 0 {
+		t.Fatalf("expected weight 0 after Clear, got %d", w)
+	}
+
+	if _, ok := sc.Retrieve("key-0"); ok {
+		t.Fatal("expected all entries gone after Clear")
+	}
+}
+
+func TestShardedWeightedCacheBudgetMatchesRequested(t *testing.T) {
+	for _, n := range []int{1, 3, 4, 7} {
+		sc := NewSharded(10, n)
+		if sc.Budget() != 10 {
+			t.Fatalf("n=%d: expected total budget 10, got %d", n, sc.Budget())
+		}
+	}
+}
+
+func TestShardedWeightedCacheConcurrentAccess(t *testing.T) {
+	sc := NewSharded(1000, 8)
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < 50; i++ {
+				key := fmt.Sprintf("g%d-k%d", g, i)
+				if err := sc.Insert(key, i, 1); err != nil {
+					t.Error(err)
+					return
+				}
+				sc.Retrieve(key)
+			}
+		}(g)
+	}
+
+	wg.Wait()
+}
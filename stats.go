@@ -0,0 +1,41 @@
+package weightedcache
+
+// SetOnEvict registers a callback invoked whenever an entry leaves the
+// cache other than via an explicit Clear — on capacity eviction, TTL
+// expiration or replacement by Upsert — so callers can release resources (close file handles,
+// decrement refcounts) tied to the evicted value.
+func (c *WeightedCacheOf[K, V]) SetOnEvict(fn func(key K, value V, weight int)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.onEvict = fn
+}
+
+// Stats is a snapshot of a WeightedCacheOf's counters, useful for
+// programmatic monitoring.
+type Stats struct {
+	Hits       int
+	Misses     int
+	Insertions int
+	Evictions  int
+	Entries    int
+	Weight     int
+	Budget     int
+}
+
+// Stats returns a snapshot of the cache's hit/miss/insertion/eviction
+// counters along with its current size.
+func (c *WeightedCacheOf[K, V]) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return Stats{
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Insertions: c.insertions,
+		Evictions:  c.evictions,
+		Entries:    len(c.lookup),
+		Weight:     c.weight,
+		Budget:     c.budget,
+	}
+}
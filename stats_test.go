@@ -0,0 +1,64 @@
+package weightedcache
+
+import "testing"
+
+func TestSetOnEvictFiresOnCapacityEviction(t *testing.T) {
+	c := New(2)
+
+	var evictedKeys []string
+	c.SetOnEvict(func(key string, value interface{}, weight int) {
+		evictedKeys = append(evictedKeys, key)
+	})
+
+	mustInsert(t, c, "a", 1, 1)
+	mustInsert(t, c, "b", 2, 1)
+	mustInsert(t, c, "c", 3, 1) // evicts "a" (least recently used)
+
+	if len(evictedKeys) != 1 || evictedKeys[0] != "a" {
+		t.Fatalf("expected onEvict to fire once for a, got %v", evictedKeys)
+	}
+}
+
+func TestStatsTracksHitsMissesInsertionsEvictions(t *testing.T) {
+	c := New(2)
+
+	mustInsert(t, c, "a", 1, 1)
+	mustInsert(t, c, "b", 2, 1)
+
+	c.Retrieve("a")    // hit
+	c.Retrieve("nope") // miss
+
+	if err := c.Insert("c", 3, 1); err != nil { // evicts b
+		t.Fatal(err)
+	}
+
+	stats := c.Stats()
+
+	if stats.Hits != 1 {
+		t.Fatalf("expected 1 hit, got %d", stats.Hits)
+	}
+
+	if stats.Misses != 1 {
+		t.Fatalf("expected 1 miss, got %d", stats.Misses)
+	}
+
+	if stats.Insertions != 3 {
+		t.Fatalf("expected 3 insertions, got %d", stats.Insertions)
+	}
+
+	if stats.Evictions != 1 {
+		t.Fatalf("expected 1 eviction, got %d", stats.Evictions)
+	}
+
+	if stats.Entries != 2 {
+		t.Fatalf("expected 2 entries, got %d", stats.Entries)
+	}
+
+	if stats.Weight != 2 {
+		t.Fatalf("expected weight 2, got %d", stats.Weight)
+	}
+
+	if stats.Budget != 2 {
+		t.Fatalf("expected budget 2, got %d", stats.Budget)
+	}
+}
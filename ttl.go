@@ -0,0 +1,71 @@
+package weightedcache
+
+import "time"
+
+// InsertWithTTL inserts an object into the cache that expires after ttl has
+// elapsed. Once expired, the entry is treated as a miss by Retrieve and is
+// removed from the cache, whether or not a janitor is running.
+func (c *WeightedCacheOf[K, V]) InsertWithTTL(key K, value V, weight int, ttl time.Duration) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.insertLocked(key, value, weight, time.Now().Add(ttl))
+}
+
+// StartJanitor starts a background goroutine that sweeps expired entries
+// out of the cache on a fixed interval, so they're reclaimed even if
+// they're never looked up again. Call StopJanitor to stop it.
+func (c *WeightedCacheOf[K, V]) StartJanitor(interval time.Duration) {
+	c.janitorMutex.Lock()
+	defer c.janitorMutex.Unlock()
+
+	if c.janitorStop != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	c.janitorStop = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.sweepExpired()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// StopJanitor stops the background janitor goroutine started by
+// StartJanitor. It is a no-op if the janitor isn't running.
+func (c *WeightedCacheOf[K, V]) StopJanitor() {
+	c.janitorMutex.Lock()
+	defer c.janitorMutex.Unlock()
+
+	if c.janitorStop == nil {
+		return
+	}
+
+	close(c.janitorStop)
+	c.janitorStop = nil
+}
+
+func (c *WeightedCacheOf[K, V]) sweepExpired() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for n := c.tail; n != nil; {
+		prev := n.prev
+
+		if n.expired() {
+			c.removeLocked(n)
+		}
+
+		n = prev
+	}
+}
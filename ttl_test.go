@@ -0,0 +1,57 @@
+package weightedcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetrieveTreatsExpiredEntryAsMiss(t *testing.T) {
+	c := New(10)
+
+	if err := c.InsertWithTTL("a", 1, 1, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.Retrieve("a"); !ok {
+		t.Fatal("expected a to be present before expiry")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := c.Retrieve("a"); ok {
+		t.Fatal("expected a to be treated as a miss after expiry")
+	}
+
+	if c.Weight() != 0 {
+		t.Fatalf("expected expired entry to be removed, weight=%d", c.Weight())
+	}
+}
+
+func TestJanitorSweepsExpiredEntries(t *testing.T) {
+	c := New(10)
+
+	if err := c.InsertWithTTL("a", 1, 1, 5*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	c.StartJanitor(5 * time.Millisecond)
+	defer c.StopJanitor()
+
+	time.Sleep(50 * time.Millisecond)
+
+	c.mutex.Lock()
+	n := len(c.lookup)
+	c.mutex.Unlock()
+
+	if n != 0 {
+		t.Fatalf("expected janitor to reap expired entry, lookup size=%d", n)
+	}
+}
+
+func TestStopJanitorIsIdempotentAndStoppable(t *testing.T) {
+	c := New(10)
+
+	c.StartJanitor(time.Millisecond)
+	c.StopJanitor()
+	c.StopJanitor() // must not panic or block when called again
+}
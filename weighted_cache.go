@@ -5,142 +5,290 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 )
 
-type key = string
+// WeightedCache is a thin alias over the generic cache, preserving the
+// original string-keyed, interface{}-valued API for backwards compatibility.
+type WeightedCache = WeightedCacheOf[string, interface{}]
 
 // New creates a new instance of a WeightedCache
 func New(budget int) *WeightedCache {
-	return &WeightedCache{lookup: make(map[key]*node), budget: budget}
+	return NewOf[string, interface{}](budget)
 }
 
-type node struct {
-	key
-	next   *node
-	prev   *node
-	value  interface{}
-	weight int
+// NewOf creates a new instance of a generic WeightedCacheOf[K, V] using the
+// default LRU eviction policy
+func NewOf[K comparable, V any](budget int) *WeightedCacheOf[K, V] {
+	return newOf[K, V](budget, PolicyLRU)
 }
 
-// WeightedCache stores arbitrary data for fast retrieval
-type WeightedCache struct {
-	head    *node
-	tail    *node
-	lookup  map[string]*node
+func newOf[K comparable, V any](budget int, policy Policy) *WeightedCacheOf[K, V] {
+	return &WeightedCacheOf[K, V]{lookup: make(map[K]*node[K, V]), budget: budget, policy: policy}
+}
+
+type node[K comparable, V any] struct {
+	key       K
+	next      *node[K, V]
+	prev      *node[K, V]
+	value     V
+	weight    int
+	visited   bool
+	expiresAt time.Time
+}
+
+// expired reports whether the node has a set expiration that has passed.
+func (n *node[K, V]) expired() bool {
+	return !n.expiresAt.IsZero() && time.Now().After(n.expiresAt)
+}
+
+// WeightedCacheOf stores arbitrary data for fast retrieval, keyed by K
+// and holding values of type V.
+type WeightedCacheOf[K comparable, V any] struct {
+	head    *node[K, V]
+	tail    *node[K, V]
+	hand    *node[K, V]
+	lookup  map[K]*node[K, V]
 	weight  int
 	budget  int
+	policy  Policy
 	verbose bool
 	mutex   sync.Mutex
 	logger  io.Writer
+	onEvict func(key K, value V, weight int)
+
+	janitorMutex sync.Mutex
+	janitorStop  chan struct{}
+
+	hits, misses, insertions, evictions int
+
+	computeMutex sync.Mutex
+	calls        map[K]*call[V]
 }
 
 // SetVerbose turns on verbose printing (warnings and stuff)
-func (c *WeightedCache) SetVerbose(verbose bool) {
+func (c *WeightedCacheOf[K, V]) SetVerbose(verbose bool) {
 	c.verbose = verbose
 }
 
 // Weight gets the "weight" of a cache
-func (c *WeightedCache) Weight() int {
+func (c *WeightedCacheOf[K, V]) Weight() int {
 	return c.weight
 }
 
 // Budget gets the memory budget of a cache
-func (c *WeightedCache) Budget() int {
+func (c *WeightedCacheOf[K, V]) Budget() int {
 	return c.budget
 }
 
 const (
-	fmtErrEvict = "evicting %s (%d) for %s (%d); spare weight is now %d"
+	fmtErrEvict = "evicting %v (%d) for %v (%d); spare weight is now %d"
 )
 
 // Insert inserts an object into the cache
-func (c *WeightedCache) Insert(key string, value interface{}, weight int) error {
+func (c *WeightedCacheOf[K, V]) Insert(key K, value V, weight int) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	return c.insertLocked(key, value, weight, time.Time{})
+}
+
+func (c *WeightedCacheOf[K, V]) insertLocked(key K, value V, weight int, expiresAt time.Time) error {
 	if _, found := c.lookup[key]; found {
 		return errors.New("key already exists in WeightedCache")
 	}
 
-	node := &node{
-		key:    key,
-		value:  value,
-		weight: weight,
-		next:   c.head,
+	// reuse the backing struct of whatever we evict to make room, so that
+	// inserting at capacity does not allocate a new node
+	var n *node[K, V]
+	var logErr error
+
+	for c.tail != nil && c.weight+weight > c.budget {
+		evicted := c.evict()
+
+		if c.verbose && c.logger != nil && logErr == nil {
+			msg := fmt.Sprintf(fmtErrEvict, evicted.key, evicted.weight, key, weight, c.budget-c.weight)
+			_, logErr = c.logger.Write([]byte(msg))
+		}
+
+		n = evicted
+	}
+
+	if n == nil {
+		n = &node[K, V]{}
 	}
 
+	n.key = key
+	n.value = value
+	n.weight = weight
+	n.visited = false
+	n.expiresAt = expiresAt
+	n.prev = nil
+	n.next = c.head
+
 	if c.head != nil {
-		c.head.prev = node
+		c.head.prev = n
 	}
 
-	c.head = node
+	c.head = n
 	if c.tail == nil {
-		c.tail = node
+		c.tail = n
 	}
 
-	c.lookup[key] = node
-	c.weight += node.weight
+	c.lookup[key] = n
+	c.weight += weight
+	c.insertions++
+
+	return logErr
+}
+
+// evict removes one node from the cache according to the configured
+// eviction policy, unlinks it from the list, deletes it from the lookup
+// map and returns it so its backing struct can be reused by the caller.
+func (c *WeightedCacheOf[K, V]) evict() *node[K, V] {
+	if c.policy == PolicySIEVE {
+		return c.evictSIEVE()
+	}
+
+	return c.evictLRU()
+}
+
+func (c *WeightedCacheOf[K, V]) evictLRU() *node[K, V] {
+	victim := c.tail
+	c.removeLocked(victim)
+
+	return victim
+}
+
+// evictSIEVE walks the hand backwards (towards head) from its current
+// position, clearing visited bits, and evicts the first node whose
+// visited bit is already false.
+func (c *WeightedCacheOf[K, V]) evictSIEVE() *node[K, V] {
+	h := c.hand
+	if h == nil {
+		h = c.tail
+	}
 
-	for ; c.tail != nil && c.tail != c.head && c.weight > c.budget; c.tail = c.tail.prev {
-		c.weight -= c.tail.weight
-		c.tail.prev.next = nil
+	for h.visited {
+		h.visited = false
 
-		if c.verbose && c.logger != nil {
-			msg := fmt.Sprintf(fmtErrEvict, c.tail.key, c.tail.weight, key, weight, c.budget - c.weight)
-			if _, err := c.logger.Write(([]byte)(msg)); err != nil {
-				return err
-			}
+		if h.prev != nil {
+			h = h.prev
+		} else {
+			h = c.tail
 		}
+	}
+
+	victim := h
+	c.hand = victim.prev
+	c.removeLocked(victim)
+
+	return victim
+}
+
+// removeLocked unlinks n, as unlinkLocked does, and additionally counts it
+// as an eviction and fires onEvict. Callers must hold c.mutex.
+func (c *WeightedCacheOf[K, V]) removeLocked(n *node[K, V]) {
+	c.evictions++
 
-		delete(c.lookup, c.tail.key)
+	if c.onEvict != nil {
+		c.onEvict(n.key, n.value, n.weight)
 	}
 
-	return nil
+	c.unlinkLocked(n)
+}
+
+// unlinkLocked removes n from the list, detaches the hand if it points at
+// n, subtracts its weight and deletes it from the lookup map, without
+// treating the removal as an eviction. Callers must hold c.mutex.
+func (c *WeightedCacheOf[K, V]) unlinkLocked(n *node[K, V]) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	}
+
+	if n.next != nil {
+		n.next.prev = n.prev
+	}
+
+	if n == c.head {
+		c.head = n.next
+	}
+
+	if n == c.tail {
+		c.tail = n.prev
+	}
+
+	if n == c.hand {
+		c.hand = n.prev
+	}
+
+	c.weight -= n.weight
+
+	delete(c.lookup, n.key)
 }
 
 // Retrieve gets an object out of the cache
-func (c *WeightedCache) Retrieve(key string) (interface{}, bool) {
+func (c *WeightedCacheOf[K, V]) Retrieve(key K) (V, bool) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	node, found := c.lookup[key]
+	n, found := c.lookup[key]
 	if !found {
-		return nil, false
+		c.misses++
+
+		var zero V
+		return zero, false
+	}
+
+	if n.expired() {
+		c.removeLocked(n)
+		c.misses++
+
+		var zero V
+		return zero, false
+	}
+
+	c.hits++
+
+	if c.policy == PolicySIEVE {
+		n.visited = true
+		return n.value, true
 	}
 
-	if node != c.head {
-		if node.next != nil {
-			node.next.prev = node.prev
+	if n != c.head {
+		if n.next != nil {
+			n.next.prev = n.prev
 		}
 
-		if node.prev != nil {
-			node.prev.next = node.next
+		if n.prev != nil {
+			n.prev.next = n.next
 		}
 
-		if node == c.tail {
+		if n == c.tail {
 			c.tail = c.tail.prev
 		}
 
-		node.next = c.head
-		node.prev = nil
+		n.next = c.head
+		n.prev = nil
 
 		if c.head != nil {
-			c.head.prev = node
+			c.head.prev = n
 		}
 
-		c.head = node
+		c.head = n
 	}
 
-	return node.value, true
+	return n.value, true
 }
 
 // Clear removes all cache entries
-func (c *WeightedCache) Clear() {
+func (c *WeightedCacheOf[K, V]) Clear() {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
 	c.head = nil
 	c.tail = nil
-	c.lookup = make(map[string]*node)
+	c.hand = nil
+	c.lookup = make(map[K]*node[K, V])
 	c.weight = 0
 }
@@ -0,0 +1,92 @@
+package weightedcache
+
+import "testing"
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New(3)
+
+	mustInsert(t, c, "a", 1, 1)
+	mustInsert(t, c, "b", 2, 1)
+	mustInsert(t, c, "c", 3, 1)
+
+	// touching a promotes it to the head, leaving b as the least recent
+	c.Retrieve("a")
+
+	if err := c.Insert("d", 4, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.Retrieve("b"); ok {
+		t.Fatal("expected b (least recently used) to be evicted")
+	}
+
+	if _, ok := c.Retrieve("a"); !ok {
+		t.Fatal("expected a (recently touched) to survive")
+	}
+
+	if _, ok := c.Retrieve("d"); !ok {
+		t.Fatal("expected newly inserted d to be present")
+	}
+}
+
+func TestInsertRejectsDuplicateKey(t *testing.T) {
+	c := New(10)
+
+	mustInsert(t, c, "a", 1, 1)
+
+	if err := c.Insert("a", 2, 1); err == nil {
+		t.Fatal("expected inserting an existing key to return an error")
+	}
+}
+
+// TestNewOfAcceptsNonStringComparableKeys exercises the generic
+// constructor with a fixed-size array key, as used by DNS-style caches
+// hashing lookups into [32]byte.
+func TestNewOfAcceptsNonStringComparableKeys(t *testing.T) {
+	c := NewOf[[32]byte, string](10)
+
+	var key [32]byte
+	key[0] = 0xAB
+
+	if err := c.Insert(key, "example.com", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	v, ok := c.Retrieve(key)
+	if !ok || v != "example.com" {
+		t.Fatalf("expected example.com, got %v ok=%v", v, ok)
+	}
+}
+
+func TestInsertAtCapacityAllocatesNoNewNode(t *testing.T) {
+	c := New(3)
+
+	mustInsert(t, c, "a", 1, 1)
+	mustInsert(t, c, "b", 2, 1)
+	mustInsert(t, c, "c", 3, 1)
+
+	// keys and values are precomputed so the AllocsPerRun closure only
+	// exercises Insert's own allocation behavior; AllocsPerRun does one
+	// warm-up call in addition to the requested runs, hence the +1
+	const runs = 10
+
+	keys := make([]string, runs+1)
+	values := make([]interface{}, runs+1)
+
+	for j := range keys {
+		keys[j] = string([]byte{'k', byte(j)})
+		values[j] = j % 256
+	}
+
+	i := 0
+	allocs := testing.AllocsPerRun(runs, func() {
+		if err := c.Insert(keys[i], values[i], 1); err != nil {
+			t.Fatal(err)
+		}
+		i++
+	})
+
+	if allocs > 0 {
+		t.Fatalf("expected zero allocations inserting at capacity, got %v", allocs)
+	}
+}